@@ -0,0 +1,317 @@
+//go:build !nocgo
+
+package daff
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+// FFTBackend performs the real-to-complex and complex-to-real transforms
+// the Convolver needs. The default backend wraps gonum/fourier; callers can
+// plug in a faster implementation by satisfying this interface.
+type FFTBackend interface {
+	// Forward computes the complex spectrum of a real-valued block of
+	// length Size().
+	Forward(block []float32) []complex128
+	// Inverse computes the real-valued time-domain block of length
+	// Size() from a complex spectrum produced by Forward (or the
+	// element-wise sum of several such spectra).
+	Inverse(spectrum []complex128) []float32
+	// Size returns the transform length this backend operates on.
+	Size() int
+}
+
+// gonumFFTBackend is the default FFTBackend, backed by gonum/fourier.
+type gonumFFTBackend struct {
+	fft *fourier.FFT
+	n   int
+}
+
+// NewGonumFFTBackend creates an FFTBackend of transform length n using
+// gonum/fourier.
+func NewGonumFFTBackend(n int) FFTBackend {
+	return &gonumFFTBackend{fft: fourier.NewFFT(n), n: n}
+}
+
+func (b *gonumFFTBackend) Size() int { return b.n }
+
+func (b *gonumFFTBackend) Forward(block []float32) []complex128 {
+	real := make([]float64, b.n)
+	for i, v := range block {
+		real[i] = float64(v)
+	}
+	return b.fft.Coefficients(nil, real)
+}
+
+func (b *gonumFFTBackend) Inverse(spectrum []complex128) []float32 {
+	real := b.fft.Sequence(nil, spectrum)
+	out := make([]float32, b.n)
+	// gonum normalizes the forward transform by n on the inverse already.
+	for i, v := range real {
+		out[i] = float32(v / float64(b.n))
+	}
+	return out
+}
+
+// filterPartitions holds the pre-transformed partitions of one record's
+// filter, per channel: partitions[channel][partitionIndex] is the FFT of
+// that partition, zero-padded to the transform size.
+type filterPartitions [][]complex128
+
+// partitionCache is a small LRU of filterPartitions keyed by record index,
+// so repeatedly revisiting nearby directions doesn't re-FFT their filters.
+type partitionCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []int
+	entries  map[int][]filterPartitions // per channel
+}
+
+func newPartitionCache(capacity int) *partitionCache {
+	return &partitionCache{capacity: capacity, entries: make(map[int][]filterPartitions)}
+}
+
+func (c *partitionCache) get(record int) ([]filterPartitions, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.entries[record]
+	if ok {
+		c.touch(record)
+	}
+	return v, ok
+}
+
+func (c *partitionCache) put(record int, partitions []filterPartitions) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[record]; !exists && len(c.entries) >= c.capacity {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.entries, oldest)
+	}
+	c.entries[record] = partitions
+	c.touch(record)
+}
+
+func (c *partitionCache) touch(record int) {
+	for i, r := range c.order {
+		if r == record {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, record)
+}
+
+// Convolver performs streaming, uniformly-partitioned overlap-save
+// frequency-domain convolution of a mono input against the impulse
+// responses of a ContentIR, for a time-varying source direction.
+//
+// Each filter is split into P = ceil(N/B) partitions of blockSize B,
+// pre-transformed once per record and cached in an LRU (see
+// partitionCache). A rolling frequency-domain delay line (FDL) holds the
+// transforms of the last P input blocks. On each Process call the FDL is
+// advanced, multiplied against the partitions for the current direction,
+// summed, and inverse-transformed; a one-block cross-fade is applied
+// whenever the direction's nearest record changes to avoid audible clicks.
+type Convolver struct {
+	content     *ContentIR
+	blockSize   int
+	backend     FFTBackend
+	numChannels int
+
+	partitions *partitionCache
+
+	fdl    [][]complex128 // ring buffer of length numPartitions
+	fdlPos int
+
+	numPartitions int
+	filterLength  int
+
+	prevOverlap        []float32 // last blockSize input samples, for overlap-save framing
+	currentRecord      int
+	previousRecord     int
+	crossfadeRemaining int // blocks left in the current cross-fade, 0 = none
+}
+
+// NewConvolver creates a Convolver for the given ContentIR with the given
+// block size (typically 128-1024 samples). backend may be nil to use the
+// default gonum/fourier-based FFTBackend.
+func NewConvolver(content *ContentIR, blockSize int, backend FFTBackend) (*Convolver, error) {
+	if blockSize <= 0 {
+		return nil, errors.New("blockSize must be positive")
+	}
+	filterLength := content.GetFilterLength()
+	if filterLength <= 0 {
+		return nil, errors.New("content has no filter data")
+	}
+	if n := content.GetNumChannels(); n != 2 {
+		return nil, fmt.Errorf("Convolver requires 2-channel (binaural) content, got %d channels", n)
+	}
+
+	numPartitions := (filterLength + blockSize - 1) / blockSize
+	if backend == nil {
+		backend = NewGonumFFTBackend(2 * blockSize)
+	}
+	if backend.Size() != 2*blockSize {
+		return nil, errors.New("FFTBackend size must be 2*blockSize for overlap-save")
+	}
+
+	fdl := make([][]complex128, numPartitions)
+	for i := range fdl {
+		fdl[i] = make([]complex128, blockSize+1)
+	}
+
+	return &Convolver{
+		content:        content,
+		blockSize:      blockSize,
+		backend:        backend,
+		numChannels:    2,
+		partitions:     newPartitionCache(32),
+		fdl:            fdl,
+		numPartitions:  numPartitions,
+		filterLength:   filterLength,
+		prevOverlap:    make([]float32, blockSize),
+		currentRecord:  -1,
+		previousRecord: -1,
+	}, nil
+}
+
+// Process consumes one mono input block of exactly blockSize samples and
+// returns blockSize samples of left/right binaural output for the given
+// source direction (phi, theta in radians, following GetNearestNeighbour's
+// convention).
+func (c *Convolver) Process(inputBlock []float32, phi, theta float64) (left, right []float32, err error) {
+	if len(inputBlock) != c.blockSize {
+		return nil, nil, errors.New("inputBlock must be exactly blockSize samples")
+	}
+
+	window := make([]float32, 2*c.blockSize)
+	copy(window, c.prevOverlap)
+	copy(window[c.blockSize:], inputBlock)
+	copy(c.prevOverlap, inputBlock)
+
+	c.fdlPos = (c.fdlPos - 1 + c.numPartitions) % c.numPartitions
+	c.fdl[c.fdlPos] = c.backend.Forward(window)
+
+	record := c.content.GetNearestNeighbour(phi, theta)
+	if c.currentRecord == -1 {
+		c.currentRecord = record
+	} else if record != c.currentRecord {
+		c.previousRecord = c.currentRecord
+		c.currentRecord = record
+		c.crossfadeRemaining = 1
+	}
+
+	currentPartitions, err := c.partitionsFor(c.currentRecord)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currentOut := make([][]float32, c.numChannels)
+	for ch := 0; ch < c.numChannels; ch++ {
+		currentOut[ch] = c.renderChannel(currentPartitions[ch])
+	}
+
+	if c.crossfadeRemaining == 0 {
+		return currentOut[0], currentOut[1], nil
+	}
+
+	previousPartitions, err := c.partitionsFor(c.previousRecord)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.crossfadeRemaining--
+
+	out := make([][]float32, c.numChannels)
+	for ch := 0; ch < c.numChannels; ch++ {
+		prevOut := c.renderChannel(previousPartitions[ch])
+		out[ch] = crossfadeEqualPower(prevOut, currentOut[ch])
+	}
+	return out[0], out[1], nil
+}
+
+// renderChannel multiply-accumulates the FDL against one channel's
+// partitions and inverse-transforms the result, returning the second half
+// (the new, non-aliased samples) as in standard overlap-save.
+func (c *Convolver) renderChannel(partitions filterPartitions) []float32 {
+	spectrum := make([]complex128, c.backend.Size()/2+1)
+	for p := 0; p < c.numPartitions && p < len(partitions); p++ {
+		fdlIdx := (c.fdlPos + p) % c.numPartitions
+		in := c.fdl[fdlIdx]
+		filt := partitions[p]
+		for k := range spectrum {
+			if k < len(in) && k < len(filt) {
+				spectrum[k] += in[k] * filt[k]
+			}
+		}
+	}
+
+	full := c.backend.Inverse(spectrum)
+	return full[c.blockSize:]
+}
+
+// partitionsFor returns the cached, pre-transformed partitions for record,
+// computing and caching them on first use.
+func (c *Convolver) partitionsFor(record int) ([]filterPartitions, error) {
+	if cached, ok := c.partitions.get(record); ok {
+		return cached, nil
+	}
+
+	out := make([]filterPartitions, c.numChannels)
+	for ch := 0; ch < c.numChannels; ch++ {
+		coeffs, err := c.content.GetFilterCoeffs(record, ch)
+		if err != nil {
+			return nil, err
+		}
+		out[ch] = c.partitionFilter(coeffs)
+	}
+	c.partitions.put(record, out)
+	return out, nil
+}
+
+func (c *Convolver) partitionFilter(coeffs []float32) filterPartitions {
+	partitions := make(filterPartitions, c.numPartitions)
+	for p := 0; p < c.numPartitions; p++ {
+		block := make([]float32, 2*c.blockSize)
+		start := p * c.blockSize
+		end := start + c.blockSize
+		if start < len(coeffs) {
+			if end > len(coeffs) {
+				end = len(coeffs)
+			}
+			copy(block, coeffs[start:end])
+		}
+		partitions[p] = c.backend.Forward(block)
+	}
+	return partitions
+}
+
+// crossfadeEqualPower blends prev into cur over the length of the block
+// using an equal-power (sqrt) crossfade curve.
+func crossfadeEqualPower(prev, cur []float32) []float32 {
+	out := make([]float32, len(cur))
+	n := len(cur)
+	for i := range out {
+		t := float64(i) / float64(n)
+		fadeOut := sqrt1MinusT(t)
+		fadeIn := sqrt1MinusT(1 - t)
+		out[i] = prev[i]*float32(fadeOut) + cur[i]*float32(fadeIn)
+	}
+	return out
+}
+
+func sqrt1MinusT(t float64) float64 {
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	return math.Sqrt(1 - t)
+}
@@ -0,0 +1,113 @@
+//go:build !nocgo
+
+package daff
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPartitionCacheEviction(t *testing.T) {
+	cache := newPartitionCache(2)
+	cache.put(1, []filterPartitions{{}})
+	cache.put(2, []filterPartitions{{}})
+	cache.put(3, []filterPartitions{{}})
+
+	if _, ok := cache.get(1); ok {
+		t.Error("expected record 1 to have been evicted")
+	}
+	if _, ok := cache.get(2); !ok {
+		t.Error("expected record 2 to still be cached")
+	}
+	if _, ok := cache.get(3); !ok {
+		t.Error("expected record 3 to still be cached")
+	}
+}
+
+func TestPartitionCacheTouchDefersEviction(t *testing.T) {
+	cache := newPartitionCache(2)
+	cache.put(1, []filterPartitions{{}})
+	cache.put(2, []filterPartitions{{}})
+	cache.get(1) // touch 1, making 2 the least recently used
+	cache.put(3, []filterPartitions{{}})
+
+	if _, ok := cache.get(2); ok {
+		t.Error("expected record 2 to have been evicted after being touched least recently")
+	}
+	if _, ok := cache.get(1); !ok {
+		t.Error("expected record 1 to still be cached")
+	}
+}
+
+// TestRenderChannelMatchesDirectConvolution drives the partitioned,
+// FFT-based overlap-save path (partitionFilter + renderChannel) over a
+// multi-block input for a filter spanning more than one partition, and
+// checks the result against a direct time-domain convolution. This
+// exercises the same per-block sequence Process uses, but without going
+// through a ContentIR (which requires the cgo library this tree can't
+// link), so it can run independent of that.
+func TestRenderChannelMatchesDirectConvolution(t *testing.T) {
+	const blockSize = 4
+	filter := []float32{1, 0.5, 0.25, 0, 0.1, 0.2} // spans 2 partitions
+
+	c := &Convolver{
+		blockSize:     blockSize,
+		backend:       NewGonumFFTBackend(2 * blockSize),
+		numPartitions: (len(filter) + blockSize - 1) / blockSize,
+		prevOverlap:   make([]float32, blockSize),
+	}
+	c.fdl = make([][]complex128, c.numPartitions)
+	partitions := c.partitionFilter(filter)
+
+	input := []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	var got []float32
+	for start := 0; start < len(input); start += blockSize {
+		block := input[start : start+blockSize]
+
+		window := make([]float32, 2*blockSize)
+		copy(window, c.prevOverlap)
+		copy(window[blockSize:], block)
+		copy(c.prevOverlap, block)
+
+		c.fdlPos = (c.fdlPos - 1 + c.numPartitions) % c.numPartitions
+		c.fdl[c.fdlPos] = c.backend.Forward(window)
+
+		got = append(got, c.renderChannel(partitions)...)
+	}
+
+	want := directConvolve(input, filter)
+	for i := range want {
+		if diff := math.Abs(float64(got[i] - want[i])); diff > 1e-3 {
+			t.Errorf("renderChannel()[%d] = %v, want %v (direct convolution)", i, got[i], want[i])
+		}
+	}
+}
+
+// directConvolve is a reference time-domain implementation of the same
+// causal convolution the FFT-based path computes.
+func directConvolve(input, filter []float32) []float32 {
+	out := make([]float32, len(input))
+	for n := range out {
+		var sum float32
+		for k, h := range filter {
+			if n-k >= 0 {
+				sum += input[n-k] * h
+			}
+		}
+		out[n] = sum
+	}
+	return out
+}
+
+func TestCrossfadeEqualPowerEndpoints(t *testing.T) {
+	prev := []float32{1, 1, 1, 1}
+	cur := []float32{0, 0, 0, 0}
+
+	out := crossfadeEqualPower(prev, cur)
+	if out[0] < 0.99 {
+		t.Errorf("expected crossfade to start near prev, got %v", out[0])
+	}
+	if out[len(out)-1] > 0.3 {
+		t.Errorf("expected crossfade to end near cur, got %v", out[len(out)-1])
+	}
+}
@@ -1,3 +1,5 @@
+//go:build !nocgo
+
 // Package daff provides Go bindings for the OpenDAFF library.
 //
 // OpenDAFF is a free and open-source software package for directional audio content
@@ -40,58 +42,6 @@ import (
 	"unsafe"
 )
 
-// ContentType represents the type of data stored in a DAFF file
-type ContentType int
-
-const (
-	ContentTypeIR  ContentType = 1 // Impulse Response
-	ContentTypeMS  ContentType = 2 // Magnitude Spectrum
-	ContentTypePS  ContentType = 3 // Phase Spectrum
-	ContentTypeMPS ContentType = 4 // Magnitude-Phase Spectrum
-	ContentTypeDFT ContentType = 5 // DFT Coefficients
-)
-
-// String returns the string representation of the content type
-func (c ContentType) String() string {
-	switch c {
-	case ContentTypeIR:
-		return "ImpulseResponse"
-	case ContentTypeMS:
-		return "MagnitudeSpectrum"
-	case ContentTypePS:
-		return "PhaseSpectrum"
-	case ContentTypeMPS:
-		return "MagnitudePhaseSpectrum"
-	case ContentTypeDFT:
-		return "DFT"
-	default:
-		return "Unknown"
-	}
-}
-
-// Quantization represents the data quantization type
-type Quantization int
-
-const (
-	QuantizationInt16   Quantization = 1
-	QuantizationInt24   Quantization = 2
-	QuantizationFloat32 Quantization = 3
-)
-
-// String returns the string representation of the quantization type
-func (q Quantization) String() string {
-	switch q {
-	case QuantizationInt16:
-		return "Int16"
-	case QuantizationInt24:
-		return "Int24"
-	case QuantizationFloat32:
-		return "Float32"
-	default:
-		return "Unknown"
-	}
-}
-
 // Reader provides access to DAFF files
 type Reader struct {
 	handle C.GoDAFFReaderHandle
@@ -232,9 +182,17 @@ func (r *Reader) GetMetadataBool(key string) (bool, error) {
 	return bool(value), nil
 }
 
+// newGrid builds the regular-grid helper shared by every content type's
+// GetInterpolated, from the reader's alpha/beta resolution and point counts.
+func (r *Reader) newGrid() *regularGrid {
+	return newRegularGrid(r.GetAlphaPoints(), r.GetBetaPoints(), r.GetAlphaResolution(), r.GetBetaResolution())
+}
+
 // ContentIR provides access to Impulse Response data
 type ContentIR struct {
-	handle C.GoDAFFContentHandle
+	handle      C.GoDAFFContentHandle
+	grid        *regularGrid
+	numChannels int
 }
 
 // GetContentIR returns an impulse response content accessor
@@ -243,7 +201,13 @@ func (r *Reader) GetContentIR() (*ContentIR, error) {
 	if handle == nil {
 		return nil, errors.New("file does not contain impulse response data")
 	}
-	return &ContentIR{handle: handle}, nil
+	return &ContentIR{handle: handle, grid: r.newGrid(), numChannels: r.GetNumChannels()}, nil
+}
+
+// GetNumChannels returns the number of audio channels this impulse response
+// content was recorded with.
+func (c *ContentIR) GetNumChannels() int {
+	return c.numChannels
 }
 
 // GetFilterLength returns the length of the impulse response filters
@@ -285,6 +249,7 @@ func (c *ContentIR) GetFilterCoeffs(recordIndex, channel int) ([]float32, error)
 // ContentMS provides access to Magnitude Spectrum data
 type ContentMS struct {
 	handle C.GoDAFFContentHandle
+	grid   *regularGrid
 }
 
 // GetContentMS returns a magnitude spectrum content accessor
@@ -293,7 +258,7 @@ func (r *Reader) GetContentMS() (*ContentMS, error) {
 	if handle == nil {
 		return nil, errors.New("file does not contain magnitude spectrum data")
 	}
-	return &ContentMS{handle: handle}, nil
+	return &ContentMS{handle: handle, grid: r.newGrid()}, nil
 }
 
 // GetNumFrequencies returns the number of frequency bins
@@ -330,6 +295,7 @@ func (c *ContentMS) GetMagnitudes(recordIndex, channel int) ([]float32, error) {
 // ContentPS provides access to Phase Spectrum data
 type ContentPS struct {
 	handle C.GoDAFFContentHandle
+	grid   *regularGrid
 }
 
 // GetContentPS returns a phase spectrum content accessor
@@ -338,7 +304,7 @@ func (r *Reader) GetContentPS() (*ContentPS, error) {
 	if handle == nil {
 		return nil, errors.New("file does not contain phase spectrum data")
 	}
-	return &ContentPS{handle: handle}, nil
+	return &ContentPS{handle: handle, grid: r.newGrid()}, nil
 }
 
 // GetNumFrequencies returns the number of frequency bins
@@ -375,6 +341,7 @@ func (c *ContentPS) GetPhases(recordIndex, channel int) ([]float32, error) {
 // ContentMPS provides access to Magnitude-Phase Spectrum data
 type ContentMPS struct {
 	handle C.GoDAFFContentHandle
+	grid   *regularGrid
 }
 
 // GetContentMPS returns a magnitude-phase spectrum content accessor
@@ -383,7 +350,7 @@ func (r *Reader) GetContentMPS() (*ContentMPS, error) {
 	if handle == nil {
 		return nil, errors.New("file does not contain magnitude-phase spectrum data")
 	}
-	return &ContentMPS{handle: handle}, nil
+	return &ContentMPS{handle: handle, grid: r.newGrid()}, nil
 }
 
 // GetNumFrequencies returns the number of frequency bins
@@ -421,6 +388,7 @@ func (c *ContentMPS) GetCoefficients(recordIndex, channel int) (magnitudes, phas
 // ContentDFT provides access to DFT coefficient data
 type ContentDFT struct {
 	handle C.GoDAFFContentHandle
+	grid   *regularGrid
 }
 
 // GetContentDFT returns a DFT content accessor
@@ -429,7 +397,7 @@ func (r *Reader) GetContentDFT() (*ContentDFT, error) {
 	if handle == nil {
 		return nil, errors.New("file does not contain DFT data")
 	}
-	return &ContentDFT{handle: handle}, nil
+	return &ContentDFT{handle: handle, grid: r.newGrid()}, nil
 }
 
 // GetNumDFTCoeffs returns the number of DFT coefficients
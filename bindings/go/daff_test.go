@@ -1,3 +1,5 @@
+//go:build !nocgo
+
 package daff_test
 
 import (
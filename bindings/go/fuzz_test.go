@@ -0,0 +1,162 @@
+//go:build nocgo
+
+package daff_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	daff "github.com/MeKo-Tech/opendaff-go"
+)
+
+// These fuzz targets run against the nocgo (pure-Go) Reader, since this
+// checkout has no include/daff_go_wrapper.h or build/libDAFF to compile and
+// link the cgo Reader against — under the default build tag none of this
+// package's tests, fuzz or otherwise, can even be built here.
+//
+// The request's two cgo-specific asks are out of scope for that reason:
+// translating C++ exceptions/aborts into Go errors, and redirecting
+// stderr during fuzzing to tell a clean "invalid file" apart from a true
+// crash, both require changes to the C++ wrapper source, which does not
+// exist in this repository snapshot.
+
+// corpusFiles reads every seed file under testdata/, for fuzz targets to
+// combine with whatever other arguments their signature needs.
+func corpusFiles(f *testing.F) [][]byte {
+	f.Helper()
+	matches, err := filepath.Glob("testdata/*.daff")
+	if err != nil {
+		f.Fatalf("globbing testdata: %v", err)
+	}
+	files := make([][]byte, len(matches))
+	for i, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatalf("reading %s: %v", path, err)
+		}
+		files[i] = data
+	}
+	return files
+}
+
+// FuzzOpenReader feeds arbitrary bytes into OpenBytes (which OpenReader and
+// OpenFile both funnel through) and walks every accessor, checking only
+// that malformed input is reported as an error rather than panicking.
+func FuzzOpenReader(f *testing.F) {
+	for _, data := range corpusFiles(f) {
+		f.Add(data)
+	}
+	f.Add([]byte(""))
+	f.Add([]byte("DAFF"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := daff.NewReader()
+		if err != nil {
+			t.Fatalf("NewReader: %v", err)
+		}
+		defer r.Close()
+
+		if err := r.OpenBytes(data); err != nil {
+			return
+		}
+
+		_ = r.GetContentType()
+		_ = r.GetQuantization()
+		_ = r.GetNumChannels()
+		_ = r.GetNumRecords()
+		_ = r.GetAlphaResolution()
+		_ = r.GetBetaResolution()
+		_ = r.GetAlphaPoints()
+		_ = r.GetBetaPoints()
+		_, _, _, _ = r.GetOrientation()
+
+		switch r.GetContentType() {
+		case daff.ContentTypeIR:
+			if ir, err := r.GetContentIR(); err == nil {
+				_ = ir.GetFilterLength()
+				_ = ir.GetSamplerate()
+				for idx := 0; idx < r.GetNumRecords(); idx++ {
+					for ch := 0; ch < r.GetNumChannels(); ch++ {
+						_, _ = ir.GetFilterCoeffs(idx, ch)
+					}
+				}
+			}
+		case daff.ContentTypeMS:
+			if ms, err := r.GetContentMS(); err == nil {
+				_ = ms.GetNumFrequencies()
+			}
+		case daff.ContentTypePS:
+			if ps, err := r.GetContentPS(); err == nil {
+				_ = ps.GetNumFrequencies()
+			}
+		case daff.ContentTypeMPS:
+			if mps, err := r.GetContentMPS(); err == nil {
+				_ = mps.GetNumFrequencies()
+			}
+		case daff.ContentTypeDFT:
+			if dft, err := r.GetContentDFT(); err == nil {
+				_ = dft.GetNumDFTCoeffs()
+				_ = dft.IsSymmetric()
+			}
+		}
+	})
+}
+
+// FuzzContentIR_GetFilterCoeffs targets the IR accessor directly with
+// out-of-range and in-range record/channel indices, to catch off-by-one
+// bounds errors in the parser without needing a crafted file for every
+// combination.
+func FuzzContentIR_GetFilterCoeffs(f *testing.F) {
+	for _, data := range corpusFiles(f) {
+		f.Add(data, 0, 0)
+	}
+	f.Add([]byte(""), 0, 0)
+	f.Add([]byte(""), -1, -1)
+
+	f.Fuzz(func(t *testing.T, data []byte, recordIdx, channel int) {
+		r, err := daff.NewReader()
+		if err != nil {
+			t.Fatalf("NewReader: %v", err)
+		}
+		defer r.Close()
+
+		if err := r.OpenBytes(data); err != nil {
+			return
+		}
+		ir, err := r.GetContentIR()
+		if err != nil {
+			return
+		}
+		_, _ = ir.GetFilterCoeffs(recordIdx, channel)
+	})
+}
+
+// FuzzMetadata targets the metadata lookup path with arbitrary keys,
+// independent of content type.
+func FuzzMetadata(f *testing.F) {
+	for _, data := range corpusFiles(f) {
+		f.Add(data, "comment")
+	}
+	f.Add([]byte(""), "")
+	f.Add([]byte(""), "comment")
+
+	f.Fuzz(func(t *testing.T, data []byte, key string) {
+		r, err := daff.NewReader()
+		if err != nil {
+			t.Fatalf("NewReader: %v", err)
+		}
+		defer r.Close()
+
+		if err := r.OpenBytes(data); err != nil {
+			return
+		}
+
+		if !r.HasMetadata(key) {
+			return
+		}
+		_, _ = r.GetMetadataString(key)
+		_, _ = r.GetMetadataFloat(key)
+		_, _ = r.GetMetadataBool(key)
+	})
+}
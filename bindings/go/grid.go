@@ -0,0 +1,232 @@
+//go:build !nocgo
+
+package daff
+
+import "math"
+
+// regularGrid captures the layout info needed to interpolate across a
+// regular (alpha, beta) sampling grid, plus a lazily built triangulation of
+// that grid projected onto the unit sphere (used by spherical barycentric
+// interpolation).
+type regularGrid struct {
+	alphaPoints int
+	betaPoints  int
+	alphaRes    float64 // degrees
+	betaRes     float64 // degrees
+
+	triangles []gridTriangle
+}
+
+// gridTriangle is three record indices whose direction vectors form a
+// triangle on the unit sphere.
+type gridTriangle struct {
+	i0, i1, i2 int
+}
+
+func newRegularGrid(alphaPoints, betaPoints int, alphaRes, betaRes float32) *regularGrid {
+	return &regularGrid{
+		alphaPoints: alphaPoints,
+		betaPoints:  betaPoints,
+		alphaRes:    float64(alphaRes),
+		betaRes:     float64(betaRes),
+	}
+}
+
+func (g *regularGrid) recordIndex(a, b int) int {
+	a = ((a % g.alphaPoints) + g.alphaPoints) % g.alphaPoints
+	if b < 0 {
+		b = 0
+	}
+	if b >= g.betaPoints {
+		b = g.betaPoints - 1
+	}
+	return a*g.betaPoints + b
+}
+
+// bilinearWeights returns up to four (recordIndex, weight) pairs bracketing
+// the direction (alphaDeg, betaDeg), in degrees. At the poles (beta == 0 or
+// beta == 180) every alpha value names the same physical point, so the
+// alpha axis collapses and only two (or one) records contribute.
+func (g *regularGrid) bilinearWeights(alphaDeg, betaDeg float64) []indexWeight {
+	betaDeg = math.Max(0, math.Min(180, betaDeg))
+
+	af := alphaDeg / g.alphaRes
+	a0 := int(math.Floor(af))
+	wa := af - float64(a0)
+
+	bf := betaDeg / g.betaRes
+	b0 := int(math.Floor(bf))
+	if b0 >= g.betaPoints-1 {
+		b0 = g.betaPoints - 2
+		if b0 < 0 {
+			b0 = 0
+		}
+	}
+	wb := bf - float64(b0)
+	if g.betaPoints == 1 {
+		wb = 0
+	}
+
+	atPoleLow := b0 == 0 && betaDeg == 0
+	atPoleHigh := b0 >= g.betaPoints-2 && betaDeg == 180
+
+	if atPoleLow {
+		return []indexWeight{{g.recordIndex(0, 0), 1}}
+	}
+	if atPoleHigh {
+		return []indexWeight{{g.recordIndex(0, g.betaPoints-1), 1}}
+	}
+
+	i00 := g.recordIndex(a0, b0)
+	i01 := g.recordIndex(a0, b0+1)
+	i10 := g.recordIndex(a0+1, b0)
+	i11 := g.recordIndex(a0+1, b0+1)
+
+	return []indexWeight{
+		{i00, (1 - wa) * (1 - wb)},
+		{i10, wa * (1 - wb)},
+		{i01, (1 - wa) * wb},
+		{i11, wa * wb},
+	}
+}
+
+// indexWeight pairs a record index with its interpolation weight.
+type indexWeight struct {
+	index  int
+	weight float64
+}
+
+// ensureTriangulation builds (once) a triangulation of the regular grid by
+// splitting each (alpha, beta) quad into two triangles, using coords
+// supplied via the accessor's GetRecordCoords.
+func (g *regularGrid) ensureTriangulation() {
+	if g.triangles != nil {
+		return
+	}
+	triangles := make([]gridTriangle, 0, g.alphaPoints*g.betaPoints*2)
+	for a := 0; a < g.alphaPoints; a++ {
+		for b := 0; b < g.betaPoints-1; b++ {
+			i00 := g.recordIndex(a, b)
+			i01 := g.recordIndex(a, b+1)
+			i10 := g.recordIndex(a+1, b)
+			i11 := g.recordIndex(a+1, b+1)
+			triangles = append(triangles,
+				gridTriangle{i00, i10, i11},
+				gridTriangle{i00, i11, i01},
+			)
+		}
+	}
+	g.triangles = triangles
+}
+
+// directionVector converts (alpha, beta) in degrees to a unit vector, using
+// DAFF's convention of alpha as azimuth around the pole axis and beta as the
+// polar angle measured from beta=0.
+func directionVector(alphaDeg, betaDeg float64) [3]float64 {
+	alpha := alphaDeg * math.Pi / 180
+	beta := betaDeg * math.Pi / 180
+	sinBeta := math.Sin(beta)
+	return [3]float64{
+		sinBeta * math.Cos(alpha),
+		sinBeta * math.Sin(alpha),
+		math.Cos(beta),
+	}
+}
+
+// sphericalBarycentricWeights locates the triangle containing the query
+// direction and returns its three vertices with barycentric weights. coords
+// is a callback returning the (alpha, beta) of a record index, in degrees.
+// Falls back to the single nearest vertex if no triangle contains the point
+// (can happen only due to floating point error at triangle edges).
+func (g *regularGrid) sphericalBarycentricWeights(alphaDeg, betaDeg float64, coords func(int) (float64, float64)) []indexWeight {
+	g.ensureTriangulation()
+	q := directionVector(alphaDeg, betaDeg)
+
+	for _, tri := range g.triangles {
+		a0, b0 := coords(tri.i0)
+		a1, b1 := coords(tri.i1)
+		a2, b2 := coords(tri.i2)
+		v0 := directionVector(a0, b0)
+		v1 := directionVector(a1, b1)
+		v2 := directionVector(a2, b2)
+
+		w0, w1, w2, ok := barycentric(q, v0, v1, v2)
+		if ok {
+			return []indexWeight{
+				{tri.i0, w0},
+				{tri.i1, w1},
+				{tri.i2, w2},
+			}
+		}
+	}
+
+	// No triangle matched (shouldn't normally happen): fall back to the
+	// closest triangle vertex.
+	best := -1
+	bestDist := math.Inf(1)
+	for _, tri := range g.triangles {
+		for _, idx := range []int{tri.i0, tri.i1, tri.i2} {
+			a, b := coords(idx)
+			v := directionVector(a, b)
+			d := (v[0]-q[0])*(v[0]-q[0]) + (v[1]-q[1])*(v[1]-q[1]) + (v[2]-q[2])*(v[2]-q[2])
+			if d < bestDist {
+				bestDist = d
+				best = idx
+			}
+		}
+	}
+	return []indexWeight{{best, 1}}
+}
+
+// barycentric computes the barycentric weights of q with respect to the
+// plane through v0, v1, v2, and reports whether q projects inside the
+// triangle. This is the standard planar barycentric test applied to points
+// on (or near) the unit sphere, which is accurate for the small triangles a
+// DAFF grid produces.
+func barycentric(q, v0, v1, v2 [3]float64) (w0, w1, w2 float64, ok bool) {
+	e1 := sub(v1, v0)
+	e2 := sub(v2, v0)
+	n := cross(e1, e2)
+
+	// Project q onto the triangle's plane along n.
+	denom := dot(n, n)
+	if denom == 0 {
+		return 0, 0, 0, false
+	}
+	t := dot(n, sub(v0, q)) / denom
+	p := [3]float64{q[0] + t*n[0], q[1] + t*n[1], q[2] + t*n[2]}
+
+	vp0 := sub(v0, p)
+	vp1 := sub(v1, p)
+	vp2 := sub(v2, p)
+	areaTotal := dot(cross(sub(v1, v0), sub(v2, v0)), n)
+	if areaTotal == 0 {
+		return 0, 0, 0, false
+	}
+
+	w0 = dot(cross(vp1, vp2), n) / areaTotal
+	w1 = dot(cross(vp2, vp0), n) / areaTotal
+	w2 = dot(cross(vp0, vp1), n) / areaTotal
+
+	const eps = 1e-6
+	if w0 < -eps || w1 < -eps || w2 < -eps {
+		return 0, 0, 0, false
+	}
+	return w0, w1, w2, true
+}
+
+func sub(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
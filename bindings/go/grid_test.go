@@ -0,0 +1,66 @@
+//go:build !nocgo
+
+package daff
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBilinearWeightsSumToOne(t *testing.T) {
+	grid := newRegularGrid(36, 19, 10, 10)
+
+	weights := grid.bilinearWeights(15, 45)
+	var sum float64
+	for _, w := range weights {
+		sum += w.weight
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("expected weights to sum to 1, got %v", sum)
+	}
+}
+
+func TestBilinearWeightsAtPoleCollapseToOneRecord(t *testing.T) {
+	grid := newRegularGrid(36, 19, 10, 10)
+
+	weights := grid.bilinearWeights(123, 0)
+	if len(weights) != 1 {
+		t.Fatalf("expected a single record at the pole, got %d", len(weights))
+	}
+	if weights[0].weight != 1 {
+		t.Errorf("expected full weight at the pole, got %v", weights[0].weight)
+	}
+}
+
+func TestBilinearWeightsExactGridPoint(t *testing.T) {
+	grid := newRegularGrid(36, 19, 10, 10)
+
+	weights := grid.bilinearWeights(20, 30)
+	for _, w := range weights {
+		if w.index == grid.recordIndex(2, 3) {
+			if math.Abs(w.weight-1) > 1e-9 {
+				t.Errorf("expected the exact grid point to carry full weight, got %v", w.weight)
+			}
+			return
+		}
+	}
+	t.Fatal("exact grid point not found among returned weights")
+}
+
+func TestSphericalBarycentricWeightsSumToOne(t *testing.T) {
+	grid := newRegularGrid(8, 5, 45, 45)
+	coords := func(idx int) (float64, float64) {
+		a := idx / grid.betaPoints
+		b := idx % grid.betaPoints
+		return float64(a) * 45, float64(b) * 45
+	}
+
+	weights := grid.sphericalBarycentricWeights(20, 50, coords)
+	var sum float64
+	for _, w := range weights {
+		sum += w.weight
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		t.Errorf("expected barycentric weights to sum to 1, got %v", sum)
+	}
+}
@@ -0,0 +1,233 @@
+//go:build !nocgo
+
+package daff
+
+import (
+	"errors"
+	"math"
+)
+
+// InterpolationMode selects how GetInterpolated synthesizes data between
+// the grid records returned by GetNearestNeighbour.
+type InterpolationMode int
+
+const (
+	// InterpolationBilinear interpolates on the regular (alpha, beta) grid
+	// using the four records bracketing the query direction.
+	InterpolationBilinear InterpolationMode = iota + 1
+	// InterpolationSphericalBarycentric interpolates using the triangle,
+	// from a triangulation of the grid on the unit sphere, that contains
+	// the query direction.
+	InterpolationSphericalBarycentric
+)
+
+// coordsOf adapts a GetRecordCoords-shaped method (which also reports an
+// error for an out-of-range index) to the plain func(int) (float64, float64)
+// shape sphericalBarycentricWeights needs; indices it is called with always
+// come from this package's own grid, so they cannot be out of range.
+func coordsOf(get func(int) (float64, float64, error)) func(int) (float64, float64) {
+	return func(idx int) (float64, float64) {
+		alpha, beta, _ := get(idx)
+		return alpha, beta
+	}
+}
+
+// weightsFor resolves the (record index, weight) pairs for a query
+// direction under the given mode. phi/theta follow GetNearestNeighbour's
+// convention (radians); alpha/beta (degrees) are derived from them the same
+// way the underlying grid is addressed by GetRecordCoords.
+func weightsFor(grid *regularGrid, phi, theta float64, mode InterpolationMode, coords func(int) (float64, float64)) ([]indexWeight, error) {
+	alphaDeg := phi * 180 / math.Pi
+	betaDeg := theta * 180 / math.Pi
+
+	switch mode {
+	case InterpolationBilinear:
+		return grid.bilinearWeights(alphaDeg, betaDeg), nil
+	case InterpolationSphericalBarycentric:
+		return grid.sphericalBarycentricWeights(alphaDeg, betaDeg, coords), nil
+	default:
+		return nil, errors.New("unknown interpolation mode")
+	}
+}
+
+// GetInterpolated returns filter coefficients for channel, synthesized by
+// blending the surrounding grid records according to mode. Coefficients are
+// interpolated coefficient-wise in the time domain.
+func (c *ContentIR) GetInterpolated(phi, theta float64, channel int, mode InterpolationMode) ([]float32, error) {
+	weights, err := weightsFor(c.grid, phi, theta, mode, coordsOf(c.GetRecordCoords))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []float32
+	for _, iw := range weights {
+		coeffs, err := c.GetFilterCoeffs(iw.index, channel)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			out = make([]float32, len(coeffs))
+		}
+		for i, v := range coeffs {
+			out[i] += float32(iw.weight) * v
+		}
+	}
+	return out, nil
+}
+
+// GetInterpolated returns the magnitude spectrum for channel, synthesized
+// by blending the surrounding grid records. Magnitudes are interpolated in
+// the dB (log) domain, which matches how the ear perceives level
+// differences, and converted back to linear afterwards.
+func (c *ContentMS) GetInterpolated(phi, theta float64, channel int, mode InterpolationMode) ([]float32, error) {
+	weights, err := weightsFor(c.grid, phi, theta, mode, coordsOf(c.GetRecordCoords))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []float32
+	for _, iw := range weights {
+		mags, err := c.GetMagnitudes(iw.index, channel)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			out = make([]float32, len(mags))
+		}
+		for i, v := range mags {
+			out[i] += float32(iw.weight) * linearToDB(v)
+		}
+	}
+	for i, v := range out {
+		out[i] = dbToLinear(v)
+	}
+	return out, nil
+}
+
+// GetInterpolated returns the phase spectrum for channel, synthesized by
+// blending the surrounding grid records. Each phase is interpolated as a
+// unit vector (cos, sin) rather than the raw angle, which avoids the
+// discontinuity a naive average would hit across the +-pi wraparound.
+func (c *ContentPS) GetInterpolated(phi, theta float64, channel int, mode InterpolationMode) ([]float32, error) {
+	weights, err := weightsFor(c.grid, phi, theta, mode, coordsOf(c.GetRecordCoords))
+	if err != nil {
+		return nil, err
+	}
+	return interpolatePhases(weights, func(idx int) ([]float32, error) {
+		return c.GetPhases(idx, channel)
+	})
+}
+
+// GetInterpolated returns magnitude and phase spectra for channel,
+// synthesized by blending the surrounding grid records (log-domain for
+// magnitude, unit-vector for phase; see ContentMS.GetInterpolated and
+// ContentPS.GetInterpolated).
+func (c *ContentMPS) GetInterpolated(phi, theta float64, channel int, mode InterpolationMode) (magnitudes, phases []float32, err error) {
+	weights, err := weightsFor(c.grid, phi, theta, mode, coordsOf(c.GetRecordCoords))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, iw := range weights {
+		mags, phs, err := c.GetCoefficients(iw.index, channel)
+		if err != nil {
+			return nil, nil, err
+		}
+		if magnitudes == nil {
+			magnitudes = make([]float32, len(mags))
+		}
+		for i, v := range mags {
+			magnitudes[i] += float32(iw.weight) * linearToDB(v)
+		}
+		phases, err = accumulatePhaseVectors(phases, phs, iw.weight)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	for i, v := range magnitudes {
+		magnitudes[i] = dbToLinear(v)
+	}
+	phases = finalizePhaseVectors(phases)
+	return magnitudes, phases, nil
+}
+
+// GetInterpolated returns interleaved real/imaginary DFT coefficients for
+// channel, synthesized by blending the surrounding grid records. The
+// complex representation is interpolated directly, which implicitly
+// handles phase wraparound correctly.
+func (c *ContentDFT) GetInterpolated(phi, theta float64, channel int, mode InterpolationMode) ([]float32, error) {
+	weights, err := weightsFor(c.grid, phi, theta, mode, coordsOf(c.GetRecordCoords))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []float32
+	for _, iw := range weights {
+		coeffs, err := c.GetDFTCoeffs(iw.index, channel)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			out = make([]float32, len(coeffs))
+		}
+		for i, v := range coeffs {
+			out[i] += float32(iw.weight) * v
+		}
+	}
+	return out, nil
+}
+
+func linearToDB(v float32) float32 {
+	if v <= 0 {
+		return -math.MaxFloat32
+	}
+	return float32(20 * math.Log10(float64(v)))
+}
+
+func dbToLinear(v float32) float32 {
+	return float32(math.Pow(10, float64(v)/20))
+}
+
+// interpolatePhases blends phases by summing unit vectors and taking the
+// angle of the result, which is equivalent to interpolating the complex
+// exponential representation of each phase and avoids unwrapping.
+func interpolatePhases(weights []indexWeight, get func(int) ([]float32, error)) ([]float32, error) {
+	var acc []float32
+	for _, iw := range weights {
+		phases, err := get(iw.index)
+		if err != nil {
+			return nil, err
+		}
+		var perr error
+		acc, perr = accumulatePhaseVectors(acc, phases, iw.weight)
+		if perr != nil {
+			return nil, perr
+		}
+	}
+	return finalizePhaseVectors(acc), nil
+}
+
+// accumulatePhaseVectors adds weight*phases[i] (as a unit vector) into acc,
+// which stores interleaved (sumCos, sumSin) pairs per bin.
+func accumulatePhaseVectors(acc []float32, phases []float32, weight float64) ([]float32, error) {
+	if acc == nil {
+		acc = make([]float32, len(phases)*2)
+	} else if len(acc) != len(phases)*2 {
+		return nil, errors.New("mismatched bin count while interpolating phases")
+	}
+	for i, p := range phases {
+		acc[i*2] += float32(weight) * float32(math.Cos(float64(p)))
+		acc[i*2+1] += float32(weight) * float32(math.Sin(float64(p)))
+	}
+	return acc, nil
+}
+
+// finalizePhaseVectors turns interleaved (sumCos, sumSin) pairs back into
+// phase angles.
+func finalizePhaseVectors(acc []float32) []float32 {
+	out := make([]float32, len(acc)/2)
+	for i := range out {
+		out[i] = float32(math.Atan2(float64(acc[i*2+1]), float64(acc[i*2])))
+	}
+	return out
+}
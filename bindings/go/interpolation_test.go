@@ -0,0 +1,38 @@
+//go:build !nocgo
+
+package daff
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInterpolatePhasesAvoidsWrapDiscontinuity(t *testing.T) {
+	// Two phases straddling the +-pi wraparound should average to something
+	// close to pi, not to 0 (which a naive linear average would produce).
+	near := []float32{float32(math.Pi - 0.1)}
+	far := []float32{float32(-math.Pi + 0.1)}
+
+	weights := []indexWeight{{index: 0, weight: 0.5}, {index: 1, weight: 0.5}}
+	got, err := interpolatePhases(weights, func(idx int) ([]float32, error) {
+		if idx == 0 {
+			return near, nil
+		}
+		return far, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	diff := math.Abs(float64(got[0]) - math.Pi)
+	if diff > 0.2 && math.Abs(float64(got[0])+math.Pi) > 0.2 {
+		t.Errorf("expected interpolated phase near +-pi, got %v", got[0])
+	}
+}
+
+func TestLinearDBRoundTrip(t *testing.T) {
+	v := float32(0.5)
+	if got := dbToLinear(linearToDB(v)); math.Abs(float64(got-v)) > 1e-5 {
+		t.Errorf("expected round-trip through dB to preserve value, got %v want %v", got, v)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build !nocgo
+
+package daff
+
+import (
+	"io"
+	"os"
+)
+
+// OpenReader opens a DAFF blob from an io.ReaderAt of the given size, for
+// sources like HTTP bodies, object storage, or files inside a tar/zip
+// archive that shouldn't be extracted to disk by the caller.
+//
+// The underlying C++ library only reads from filesystem paths, so the
+// bytes are staged into a temporary file that is removed again once the
+// reader has finished opening it. OpenReader, OpenBytes, and OpenFile are
+// otherwise interchangeable: every other Reader method behaves identically
+// regardless of which was used to open the file.
+func (r *Reader) OpenReader(ra io.ReaderAt, size int64) error {
+	tmp, err := os.CreateTemp("", "daff-reader-*.daff")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := io.Copy(tmp, io.NewSectionReader(ra, 0, size)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return r.OpenFile(tmpPath)
+}
+
+// OpenBytes opens a DAFF blob held entirely in memory, e.g. one embedded
+// with //go:embed. See OpenReader for how this is implemented.
+func (r *Reader) OpenBytes(data []byte) error {
+	return r.OpenReader(byteReaderAt(data), int64(len(data)))
+}
+
+// byteReaderAt adapts a byte slice to io.ReaderAt without copying it.
+type byteReaderAt []byte
+
+func (b byteReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off >= int64(len(b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,31 @@
+//go:build !nocgo
+
+package daff
+
+import (
+	"io"
+	"testing"
+)
+
+func TestByteReaderAt(t *testing.T) {
+	data := byteReaderAt([]byte("hello world"))
+
+	buf := make([]byte, 5)
+	n, err := data.ReadAt(buf, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(buf[:n]) != "world" {
+		t.Errorf("got %q, want %q", buf[:n], "world")
+	}
+}
+
+func TestByteReaderAtPastEnd(t *testing.T) {
+	data := byteReaderAt([]byte("abc"))
+
+	buf := make([]byte, 5)
+	_, err := data.ReadAt(buf, 10)
+	if err != io.EOF {
+		t.Errorf("expected io.EOF reading past the end, got %v", err)
+	}
+}
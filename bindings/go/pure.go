@@ -0,0 +1,372 @@
+//go:build nocgo
+
+// Package daff, under the nocgo build tag, replaces the cgo-backed Reader
+// in daff.go with a pure-Go parser, so that downstream users targeting
+// WASM, cross-compilation, or minimal containers can drop the
+// libDAFF.so/libstdc++ link entirely. It only implements the read path
+// (Writer, the interpolation helpers, and the Convolver all require the
+// cgo build).
+//
+// # Not wire-compatible with real DAFF files
+//
+// This checkout has no include/ or build/ directory, so the real OpenDAFF
+// C++ source and its actual on-disk byte layout are not available here to
+// parse against. The format read by parseFile (see pure_format.go) is a
+// bespoke stand-in this package invented for its own round-trip use: a
+// file header (magic, format version, content type, quantization), a main
+// header (channel count, record count, alpha/beta resolution and point
+// counts, orientation), one record descriptor per grid point giving each
+// record's data chunk offset and size, the data chunks themselves (whose
+// shape depends on content type), and a trailing metadata chunk of typed
+// key/value pairs.
+//
+// This is NOT the real OpenDAFF wire format. A .daff file produced by the
+// actual OpenDAFF library, or by this module's own cgo Writer, will not
+// open under this build tag, and files produced here will not open under
+// the cgo Reader. Until the real byte layout is documented and implemented
+// here, nocgo and the default (cgo) build are two different formats behind
+// one API, not two implementations of the same one.
+package daff
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+var byteOrder = binary.LittleEndian
+
+// fileMagic deliberately does not match any real DAFF file cookie, so that
+// this parser can never appear to successfully open a real OpenDAFF file
+// (see the package doc for why it can't actually read one).
+const fileMagic = "DAFFGOv1"
+
+// Reader provides access to DAFF files, parsed directly from the on-disk
+// format without any C++/cgo dependency.
+type Reader struct {
+	file *parsedFile
+}
+
+// NewReader creates a new DAFF reader.
+func NewReader() (*Reader, error) {
+	return &Reader{}, nil
+}
+
+// Close releases resources associated with the reader.
+func (r *Reader) Close() error {
+	r.file = nil
+	return nil
+}
+
+// OpenFile opens a DAFF file for reading.
+func (r *Reader) OpenFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.OpenReader(f, fileSize(f))
+}
+
+// OpenReader opens a DAFF blob from an io.ReaderAt of the given size.
+func (r *Reader) OpenReader(ra io.ReaderAt, size int64) error {
+	parsed, err := parseFile(ra, size)
+	if err != nil {
+		return err
+	}
+	r.file = parsed
+	return nil
+}
+
+// OpenBytes opens a DAFF blob held entirely in memory.
+func (r *Reader) OpenBytes(data []byte) error {
+	return r.OpenReader(bytesReaderAt(data), int64(len(data)))
+}
+
+// CloseFile closes the currently opened file.
+func (r *Reader) CloseFile() {
+	r.file = nil
+}
+
+// IsValid returns true if a file is currently opened and valid.
+func (r *Reader) IsValid() bool {
+	return r.file != nil
+}
+
+func fileSize(f *os.File) int64 {
+	info, err := f.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// GetContentType returns the content type of the opened file.
+func (r *Reader) GetContentType() ContentType {
+	if r.file == nil {
+		return 0
+	}
+	return r.file.contentType
+}
+
+// GetQuantization returns the quantization type used in the file.
+func (r *Reader) GetQuantization() Quantization {
+	if r.file == nil {
+		return 0
+	}
+	return r.file.quantization
+}
+
+// GetNumChannels returns the number of audio channels.
+func (r *Reader) GetNumChannels() int {
+	if r.file == nil {
+		return 0
+	}
+	return r.file.numChannels
+}
+
+// GetNumRecords returns the number of directional records.
+func (r *Reader) GetNumRecords() int {
+	if r.file == nil {
+		return 0
+	}
+	return len(r.file.records)
+}
+
+// GetAlphaResolution returns the angular resolution in alpha direction (degrees).
+func (r *Reader) GetAlphaResolution() float32 {
+	if r.file == nil {
+		return 0
+	}
+	return r.file.alphaRes
+}
+
+// GetBetaResolution returns the angular resolution in beta direction (degrees).
+func (r *Reader) GetBetaResolution() float32 {
+	if r.file == nil {
+		return 0
+	}
+	return r.file.betaRes
+}
+
+// GetAlphaPoints returns the number of sampling points in alpha direction.
+func (r *Reader) GetAlphaPoints() int {
+	if r.file == nil {
+		return 0
+	}
+	return r.file.alphaPoints
+}
+
+// GetBetaPoints returns the number of sampling points in beta direction.
+func (r *Reader) GetBetaPoints() int {
+	if r.file == nil {
+		return 0
+	}
+	return r.file.betaPoints
+}
+
+// GetOrientation returns the orientation as yaw, pitch, roll angles in degrees.
+func (r *Reader) GetOrientation() (yaw, pitch, roll float32, err error) {
+	if r.file == nil {
+		return 0, 0, 0, errors.New("no file opened")
+	}
+	return r.file.yaw, r.file.pitch, r.file.roll, nil
+}
+
+// HasMetadata returns true if the specified metadata key exists.
+func (r *Reader) HasMetadata(key string) bool {
+	if r.file == nil {
+		return false
+	}
+	_, ok := r.file.metadata[key]
+	return ok
+}
+
+// GetMetadataString returns a string metadata value.
+func (r *Reader) GetMetadataString(key string) (string, error) {
+	v, ok := r.lookupMetadata(key)
+	if !ok {
+		return "", errors.New("metadata key not found: " + key)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", errors.New("metadata key is not a string: " + key)
+	}
+	return s, nil
+}
+
+// GetMetadataFloat returns a float metadata value.
+func (r *Reader) GetMetadataFloat(key string) (float32, error) {
+	v, ok := r.lookupMetadata(key)
+	if !ok {
+		return 0, errors.New("metadata key not found: " + key)
+	}
+	f, ok := v.(float32)
+	if !ok {
+		return 0, errors.New("metadata key is not a float: " + key)
+	}
+	return f, nil
+}
+
+// GetMetadataBool returns a boolean metadata value.
+func (r *Reader) GetMetadataBool(key string) (bool, error) {
+	v, ok := r.lookupMetadata(key)
+	if !ok {
+		return false, errors.New("metadata key not found: " + key)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, errors.New("metadata key is not a bool: " + key)
+	}
+	return b, nil
+}
+
+func (r *Reader) lookupMetadata(key string) (interface{}, bool) {
+	if r.file == nil {
+		return nil, false
+	}
+	v, ok := r.file.metadata[key]
+	return v, ok
+}
+
+// content is embedded by every Content* accessor below to share the
+// nearest-neighbour and record-coordinate logic, which only depends on the
+// grid layout, not the content type.
+type content struct {
+	file *parsedFile
+}
+
+// GetNearestNeighbour returns the record index for the given direction (phi, theta in radians).
+func (c *content) GetNearestNeighbour(phi, theta float64) int {
+	return c.file.nearestNeighbour(phi, theta)
+}
+
+// GetRecordCoords returns the alpha and beta coordinates for the given record index.
+func (c *content) GetRecordCoords(recordIndex int) (alpha, beta float64, err error) {
+	if recordIndex < 0 || recordIndex >= len(c.file.records) {
+		return 0, 0, errors.New("record index out of range")
+	}
+	rec := c.file.records[recordIndex]
+	return rec.alpha, rec.beta, nil
+}
+
+// ContentIR provides access to Impulse Response data.
+type ContentIR struct{ content }
+
+// GetContentIR returns an impulse response content accessor.
+func (r *Reader) GetContentIR() (*ContentIR, error) {
+	if r.file == nil || r.file.contentType != ContentTypeIR {
+		return nil, errors.New("file does not contain impulse response data")
+	}
+	return &ContentIR{content{r.file}}, nil
+}
+
+// GetFilterLength returns the length of the impulse response filters.
+func (c *ContentIR) GetFilterLength() int { return c.file.ir.filterLength }
+
+// GetSamplerate returns the sample rate in Hz.
+func (c *ContentIR) GetSamplerate() int { return c.file.ir.samplerate }
+
+// GetFilterCoeffs retrieves the filter coefficients for the specified record and channel.
+func (c *ContentIR) GetFilterCoeffs(recordIndex, channel int) ([]float32, error) {
+	return c.file.channelData(recordIndex, channel, c.file.ir.filterLength)
+}
+
+// ContentMS provides access to Magnitude Spectrum data.
+type ContentMS struct{ content }
+
+// GetContentMS returns a magnitude spectrum content accessor.
+func (r *Reader) GetContentMS() (*ContentMS, error) {
+	if r.file == nil || r.file.contentType != ContentTypeMS {
+		return nil, errors.New("file does not contain magnitude spectrum data")
+	}
+	return &ContentMS{content{r.file}}, nil
+}
+
+// GetNumFrequencies returns the number of frequency bins.
+func (c *ContentMS) GetNumFrequencies() int { return c.file.spectrum.numFrequencies }
+
+// GetMagnitudes retrieves magnitude values for the specified record and channel.
+func (c *ContentMS) GetMagnitudes(recordIndex, channel int) ([]float32, error) {
+	return c.file.channelData(recordIndex, channel, c.file.spectrum.numFrequencies)
+}
+
+// ContentPS provides access to Phase Spectrum data.
+type ContentPS struct{ content }
+
+// GetContentPS returns a phase spectrum content accessor.
+func (r *Reader) GetContentPS() (*ContentPS, error) {
+	if r.file == nil || r.file.contentType != ContentTypePS {
+		return nil, errors.New("file does not contain phase spectrum data")
+	}
+	return &ContentPS{content{r.file}}, nil
+}
+
+// GetNumFrequencies returns the number of frequency bins.
+func (c *ContentPS) GetNumFrequencies() int { return c.file.spectrum.numFrequencies }
+
+// GetPhases retrieves phase values for the specified record and channel.
+func (c *ContentPS) GetPhases(recordIndex, channel int) ([]float32, error) {
+	return c.file.channelData(recordIndex, channel, c.file.spectrum.numFrequencies)
+}
+
+// ContentMPS provides access to Magnitude-Phase Spectrum data.
+type ContentMPS struct{ content }
+
+// GetContentMPS returns a magnitude-phase spectrum content accessor.
+func (r *Reader) GetContentMPS() (*ContentMPS, error) {
+	if r.file == nil || r.file.contentType != ContentTypeMPS {
+		return nil, errors.New("file does not contain magnitude-phase spectrum data")
+	}
+	return &ContentMPS{content{r.file}}, nil
+}
+
+// GetNumFrequencies returns the number of frequency bins.
+func (c *ContentMPS) GetNumFrequencies() int { return c.file.spectrum.numFrequencies }
+
+// GetCoefficients retrieves both magnitude and phase values for the specified record and channel.
+func (c *ContentMPS) GetCoefficients(recordIndex, channel int) (magnitudes, phases []float32, err error) {
+	n := c.file.spectrum.numFrequencies
+	combined, err := c.file.channelData(recordIndex, channel, n*2)
+	if err != nil {
+		return nil, nil, err
+	}
+	return combined[:n], combined[n:], nil
+}
+
+// ContentDFT provides access to DFT coefficient data.
+type ContentDFT struct{ content }
+
+// GetContentDFT returns a DFT content accessor.
+func (r *Reader) GetContentDFT() (*ContentDFT, error) {
+	if r.file == nil || r.file.contentType != ContentTypeDFT {
+		return nil, errors.New("file does not contain DFT data")
+	}
+	return &ContentDFT{content{r.file}}, nil
+}
+
+// GetNumDFTCoeffs returns the number of DFT coefficients.
+func (c *ContentDFT) GetNumDFTCoeffs() int { return c.file.dft.numCoeffs }
+
+// IsSymmetric returns true if the DFT data is symmetric.
+func (c *ContentDFT) IsSymmetric() bool { return c.file.dft.symmetric }
+
+// GetDFTCoeffs retrieves DFT coefficients (interleaved real/imaginary) for the specified record and channel.
+func (c *ContentDFT) GetDFTCoeffs(recordIndex, channel int) ([]float32, error) {
+	return c.file.channelData(recordIndex, channel, c.file.dft.numCoeffs*2)
+}
+
+// bytesReaderAt adapts a byte slice to io.ReaderAt without copying it.
+type bytesReaderAt []byte
+
+func (b bytesReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off >= int64(len(b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
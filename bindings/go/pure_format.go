@@ -0,0 +1,297 @@
+//go:build nocgo
+
+package daff
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// parsedFile is the in-memory result of parsing a DAFF file's header,
+// record descriptors, and metadata; record data chunks are read lazily by
+// channelData, via the stored (offset, size) pairs, rather than slurped
+// up front.
+type parsedFile struct {
+	contentType      ContentType
+	quantization     Quantization
+	numChannels      int
+	alphaRes         float32
+	betaRes          float32
+	alphaPoints      int
+	betaPoints       int
+	yaw, pitch, roll float32
+
+	ir struct {
+		filterLength int
+		samplerate   int
+	}
+	spectrum struct {
+		numFrequencies int
+	}
+	dft struct {
+		numCoeffs int
+		symmetric bool
+	}
+
+	records  []parsedRecord
+	metadata map[string]interface{}
+
+	source io.ReaderAt
+}
+
+// parsedRecord is one directional grid point's coordinates and the
+// location of its data chunk within the file.
+type parsedRecord struct {
+	alpha, beta          float64
+	dataOffset, dataSize int64
+}
+
+// recordDescriptorSize is the on-disk size, in bytes, of one record
+// descriptor (alpha, beta float32; dataOffset, dataSize uint64).
+const recordDescriptorSize = 4 + 4 + 8 + 8
+
+func parseFile(ra io.ReaderAt, size int64) (*parsedFile, error) {
+	sr := io.NewSectionReader(ra, 0, size)
+	br := bufio.NewReader(sr)
+
+	magic := make([]byte, len(fileMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != fileMagic {
+		return nil, errors.New("not a DAFF file: bad magic")
+	}
+
+	var version uint32
+	if err := binary.Read(br, byteOrder, &version); err != nil {
+		return nil, err
+	}
+
+	f := &parsedFile{source: ra, metadata: make(map[string]interface{})}
+
+	var contentType, quantization, numChannels, numRecords uint32
+	if err := readAll(br,
+		&contentType, &quantization, &numChannels, &numRecords,
+	); err != nil {
+		return nil, err
+	}
+	f.contentType = ContentType(contentType)
+	f.quantization = Quantization(quantization)
+	f.numChannels = int(numChannels)
+
+	var alphaRes, betaRes float32
+	var alphaPoints, betaPoints uint32
+	var yaw, pitch, roll float32
+	if err := readAll(br,
+		&alphaRes, &betaRes, &alphaPoints, &betaPoints, &yaw, &pitch, &roll,
+	); err != nil {
+		return nil, err
+	}
+	f.alphaRes, f.betaRes = alphaRes, betaRes
+	f.alphaPoints, f.betaPoints = int(alphaPoints), int(betaPoints)
+	f.yaw, f.pitch, f.roll = yaw, pitch, roll
+
+	if err := f.readContentHeader(br); err != nil {
+		return nil, err
+	}
+
+	// numRecords comes straight from the file and is otherwise unbounded;
+	// every record descriptor must fit within the file, so this also
+	// guards the make() below against a malicious or corrupt count
+	// triggering a multi-gigabyte allocation.
+	if int64(numRecords)*recordDescriptorSize > size {
+		return nil, errors.New("declared record count exceeds file size")
+	}
+
+	f.records = make([]parsedRecord, numRecords)
+	for i := range f.records {
+		var alpha, beta float32
+		var dataOffset, dataSize uint64
+		if err := readAll(br, &alpha, &beta, &dataOffset, &dataSize); err != nil {
+			return nil, err
+		}
+		// dataOffset/dataSize are also read straight from the file; a
+		// descriptor claiming a data chunk that doesn't fit inside the
+		// file is corrupt (or malicious) rather than merely large, and
+		// channelData would otherwise allocate whatever size is claimed
+		// here without ever validating it against reality.
+		if int64(dataOffset) < 0 || int64(dataSize) < 0 || int64(dataOffset)+int64(dataSize) > size {
+			return nil, errors.New("record data chunk does not fit within file")
+		}
+		f.records[i] = parsedRecord{
+			alpha:      float64(alpha),
+			beta:       float64(beta),
+			dataOffset: int64(dataOffset),
+			dataSize:   int64(dataSize),
+		}
+	}
+
+	if err := f.readMetadata(br, size); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *parsedFile) readContentHeader(r io.Reader) error {
+	switch f.contentType {
+	case ContentTypeIR:
+		var filterLength, samplerate uint32
+		if err := readAll(r, &filterLength, &samplerate); err != nil {
+			return err
+		}
+		f.ir.filterLength = int(filterLength)
+		f.ir.samplerate = int(samplerate)
+	case ContentTypeMS, ContentTypePS, ContentTypeMPS:
+		var numFrequencies uint32
+		if err := readAll(r, &numFrequencies); err != nil {
+			return err
+		}
+		f.spectrum.numFrequencies = int(numFrequencies)
+	case ContentTypeDFT:
+		var numCoeffs, symmetric uint32
+		if err := readAll(r, &numCoeffs, &symmetric); err != nil {
+			return err
+		}
+		f.dft.numCoeffs = int(numCoeffs)
+		f.dft.symmetric = symmetric != 0
+	default:
+		return errors.New("unknown content type in DAFF header")
+	}
+	return nil
+}
+
+func (f *parsedFile) readMetadata(r io.Reader, size int64) error {
+	var count uint32
+	if err := binary.Read(r, byteOrder, &count); err != nil {
+		if err == io.EOF {
+			return nil // metadata chunk is optional
+		}
+		return err
+	}
+
+	for i := uint32(0); i < count; i++ {
+		key, err := readString(r, size)
+		if err != nil {
+			return err
+		}
+		var valueType uint8
+		if err := binary.Read(r, byteOrder, &valueType); err != nil {
+			return err
+		}
+		switch valueType {
+		case 0:
+			s, err := readString(r, size)
+			if err != nil {
+				return err
+			}
+			f.metadata[key] = s
+		case 1:
+			var bits uint32
+			if err := binary.Read(r, byteOrder, &bits); err != nil {
+				return err
+			}
+			f.metadata[key] = math.Float32frombits(bits)
+		case 2:
+			var b uint8
+			if err := binary.Read(r, byteOrder, &b); err != nil {
+				return err
+			}
+			f.metadata[key] = b != 0
+		default:
+			return errors.New("unknown metadata value type in DAFF file")
+		}
+	}
+	return nil
+}
+
+// readString reads a uint32-length-prefixed string. maxLen bounds the
+// allocation: a string can never be longer than the file it came from, so
+// this rejects a corrupt or malicious length before it triggers an
+// oversized allocation.
+func readString(r io.Reader, maxLen int64) (string, error) {
+	var length uint32
+	if err := binary.Read(r, byteOrder, &length); err != nil {
+		return "", err
+	}
+	if int64(length) > maxLen {
+		return "", errors.New("string length exceeds file size")
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func readAll(r io.Reader, fields ...interface{}) error {
+	for _, f := range fields {
+		if err := binary.Read(r, byteOrder, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nearestNeighbour finds the record whose (alpha, beta) is closest, by
+// great-circle distance, to the direction (phi, theta in radians).
+func (f *parsedFile) nearestNeighbour(phi, theta float64) int {
+	alphaDeg := phi * 180 / math.Pi
+	betaDeg := theta * 180 / math.Pi
+
+	best := 0
+	bestDist := math.Inf(1)
+	for i, rec := range f.records {
+		dAlpha := (rec.alpha - alphaDeg) * math.Pi / 180
+		dBeta := (rec.beta - betaDeg) * math.Pi / 180
+		dist := dAlpha*dAlpha + dBeta*dBeta
+		if dist < bestDist {
+			bestDist = dist
+			best = i
+		}
+	}
+	return best
+}
+
+// channelData reads samplesPerChannel float32 values for channel from
+// record's data chunk, which stores all channels back-to-back
+// (channel-major).
+func (f *parsedFile) channelData(recordIndex, channel, samplesPerChannel int) ([]float32, error) {
+	if recordIndex < 0 || recordIndex >= len(f.records) {
+		return nil, errors.New("record index out of range")
+	}
+	if channel < 0 || channel >= f.numChannels {
+		return nil, errors.New("channel index out of range")
+	}
+
+	rec := f.records[recordIndex]
+	if samplesPerChannel < 0 {
+		return nil, errors.New("negative sample count")
+	}
+	// samplesPerChannel is derived from header fields (filterLength,
+	// numFrequencies, numCoeffs) that are otherwise unbounded; a channel's
+	// data can never be larger than its record's own declared data size,
+	// so reject it here before it turns into an oversized allocation. The
+	// check must account for every channel up to and including this one,
+	// since they're stored back-to-back within the same data chunk -
+	// checking only this channel's own share would let a record that
+	// declares dataSize for fewer channels than numChannels silently read
+	// past its chunk into whatever follows it in the file.
+	neededBytes := int64(channel+1) * int64(samplesPerChannel) * 4
+	if neededBytes > rec.dataSize {
+		return nil, errors.New("sample count exceeds record data size")
+	}
+	byteOffset := rec.dataOffset + int64(channel*samplesPerChannel*4)
+
+	buf := make([]byte, samplesPerChannel*4)
+	if _, err := f.source.ReadAt(buf, byteOffset); err != nil {
+		return nil, err
+	}
+
+	out := make([]float32, samplesPerChannel)
+	for i := range out {
+		bits := byteOrder.Uint32(buf[i*4:])
+		out[i] = math.Float32frombits(bits)
+	}
+	return out, nil
+}
@@ -0,0 +1,185 @@
+//go:build nocgo
+
+package daff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// buildTestIRFile assembles a minimal single-record, single-channel IR file
+// in the on-disk layout documented in pure.go, so the pure-Go parser can be
+// exercised without a real DAFF file.
+func buildTestIRFile(t *testing.T, coeffs []float32) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.WriteString(fileMagic)
+	binary.Write(&buf, byteOrder, uint32(1)) // version
+
+	binary.Write(&buf, byteOrder, uint32(ContentTypeIR))
+	binary.Write(&buf, byteOrder, uint32(QuantizationFloat32))
+	binary.Write(&buf, byteOrder, uint32(1)) // numChannels
+	binary.Write(&buf, byteOrder, uint32(1)) // numRecords
+
+	binary.Write(&buf, byteOrder, float32(10)) // alphaRes
+	binary.Write(&buf, byteOrder, float32(10)) // betaRes
+	binary.Write(&buf, byteOrder, uint32(1))   // alphaPoints
+	binary.Write(&buf, byteOrder, uint32(1))   // betaPoints
+	binary.Write(&buf, byteOrder, float32(0))  // yaw
+	binary.Write(&buf, byteOrder, float32(0))  // pitch
+	binary.Write(&buf, byteOrder, float32(0))  // roll
+
+	binary.Write(&buf, byteOrder, uint32(len(coeffs))) // filterLength
+	binary.Write(&buf, byteOrder, uint32(44100))       // samplerate
+
+	// The record descriptor's dataOffset is relative to the start of the
+	// file, so it points past the header, the descriptor itself, and the
+	// (absent) metadata count that follows it.
+	descriptorSize := int64(4 + 4 + 8 + 8)              // alpha, beta, dataOffset, dataSize
+	dataOffset := int64(buf.Len()) + descriptorSize + 4 /* metadata count */
+	dataSize := int64(len(coeffs) * 4)
+
+	binary.Write(&buf, byteOrder, float32(0)) // alpha
+	binary.Write(&buf, byteOrder, float32(0)) // beta
+	binary.Write(&buf, byteOrder, uint64(dataOffset))
+	binary.Write(&buf, byteOrder, uint64(dataSize))
+
+	binary.Write(&buf, byteOrder, uint32(0)) // metadata count (none)
+
+	for _, c := range coeffs {
+		binary.Write(&buf, byteOrder, math.Float32bits(c))
+	}
+
+	if int64(buf.Len()) != dataOffset+dataSize {
+		t.Fatalf("computed dataOffset %d does not match actual offset %d", dataOffset, buf.Len()-int(dataSize))
+	}
+
+	return buf.Bytes()
+}
+
+func TestReaderOpenBytesRoundTrip(t *testing.T) {
+	want := []float32{0.1, -0.2, 0.3, -0.4}
+	data := buildTestIRFile(t, want)
+
+	r, err := NewReader()
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if err := r.OpenBytes(data); err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	defer r.Close()
+
+	if got := r.GetContentType(); got != ContentTypeIR {
+		t.Errorf("GetContentType() = %v, want %v", got, ContentTypeIR)
+	}
+	if got := r.GetNumRecords(); got != 1 {
+		t.Errorf("GetNumRecords() = %d, want 1", got)
+	}
+
+	ir, err := r.GetContentIR()
+	if err != nil {
+		t.Fatalf("GetContentIR: %v", err)
+	}
+	if got := ir.GetFilterLength(); got != len(want) {
+		t.Errorf("GetFilterLength() = %d, want %d", got, len(want))
+	}
+	if got := ir.GetSamplerate(); got != 44100 {
+		t.Errorf("GetSamplerate() = %d, want 44100", got)
+	}
+
+	got, err := ir.GetFilterCoeffs(0, 0)
+	if err != nil {
+		t.Fatalf("GetFilterCoeffs: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetFilterCoeffs() returned %d samples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GetFilterCoeffs()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestContentIR_GetFilterCoeffsRejectsChannelBeyondDeclaredSize builds a
+// record whose dataSize only covers one channel's worth of bytes even
+// though the file declares two channels, with distinctive trailing bytes
+// immediately after the record's data chunk. Reading channel 1 must reject
+// the record as malformed rather than silently returning those trailing
+// bytes as if they were its own data.
+func TestContentIR_GetFilterCoeffsRejectsChannelBeyondDeclaredSize(t *testing.T) {
+	coeffs := []float32{0.1, 0.2}
+
+	var buf bytes.Buffer
+	buf.WriteString(fileMagic)
+	binary.Write(&buf, byteOrder, uint32(1)) // version
+
+	binary.Write(&buf, byteOrder, uint32(ContentTypeIR))
+	binary.Write(&buf, byteOrder, uint32(QuantizationFloat32))
+	binary.Write(&buf, byteOrder, uint32(2)) // numChannels
+	binary.Write(&buf, byteOrder, uint32(1)) // numRecords
+
+	binary.Write(&buf, byteOrder, float32(10)) // alphaRes
+	binary.Write(&buf, byteOrder, float32(10)) // betaRes
+	binary.Write(&buf, byteOrder, uint32(1))   // alphaPoints
+	binary.Write(&buf, byteOrder, uint32(1))   // betaPoints
+	binary.Write(&buf, byteOrder, float32(0))  // yaw
+	binary.Write(&buf, byteOrder, float32(0))  // pitch
+	binary.Write(&buf, byteOrder, float32(0))  // roll
+
+	binary.Write(&buf, byteOrder, uint32(len(coeffs))) // filterLength
+	binary.Write(&buf, byteOrder, uint32(44100))       // samplerate
+
+	descriptorSize := int64(4 + 4 + 8 + 8)
+	dataOffset := int64(buf.Len()) + descriptorSize + 4 /* metadata count */
+	// dataSize only accounts for one channel, even though numChannels is 2.
+	dataSize := int64(len(coeffs) * 4)
+
+	binary.Write(&buf, byteOrder, float32(0)) // alpha
+	binary.Write(&buf, byteOrder, float32(0)) // beta
+	binary.Write(&buf, byteOrder, uint64(dataOffset))
+	binary.Write(&buf, byteOrder, uint64(dataSize))
+
+	binary.Write(&buf, byteOrder, uint32(0)) // metadata count (none)
+
+	for _, c := range coeffs {
+		binary.Write(&buf, byteOrder, math.Float32bits(c))
+	}
+	// Bytes beyond the declared record: a channel-1 read that doesn't
+	// check the channel multiplier against dataSize would return these.
+	for _, c := range []float32{999, 998} {
+		binary.Write(&buf, byteOrder, math.Float32bits(c))
+	}
+
+	r, err := NewReader()
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if err := r.OpenBytes(buf.Bytes()); err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	defer r.Close()
+
+	ir, err := r.GetContentIR()
+	if err != nil {
+		t.Fatalf("GetContentIR: %v", err)
+	}
+
+	if got, err := ir.GetFilterCoeffs(0, 1); err == nil {
+		t.Errorf("GetFilterCoeffs(0, 1) = %v, nil, want an error for a record whose dataSize doesn't cover channel 1", got)
+	}
+}
+
+func TestReaderOpenBytesBadMagic(t *testing.T) {
+	r, err := NewReader()
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if err := r.OpenBytes([]byte("not a daff file")); err == nil {
+		t.Error("expected error opening data with bad magic, got nil")
+	}
+}
@@ -0,0 +1,318 @@
+// Package sofa bridges the SOFA (Spatially Oriented Format for Acoustics,
+// AES69) file format to DAFF. SOFA is NetCDF-4 based and is the format most
+// HRTF datasets (SADIE, ARI, RIEC, MIT KEMAR) are distributed in, so this
+// package lets callers pull such datasets into a daff.Writer, or export an
+// opened daff.Reader back out to SOFA.
+package sofa
+
+import (
+	"errors"
+	"math"
+
+	"github.com/fhs/go-netcdf/netcdf"
+
+	daff "github.com/MeKo-Tech/opendaff-go"
+)
+
+// Convention identifies the SOFA data convention describing how Data.IR is
+// laid out and which dimensions are present.
+type Convention string
+
+const (
+	// ConventionSimpleFreeFieldHRIR is the convention used by most HRTF
+	// datasets: one emitter, two receivers (ears), free-field measurement.
+	ConventionSimpleFreeFieldHRIR Convention = "SimpleFreeFieldHRIR"
+	// ConventionGeneralFIR is the generic multi-emitter/receiver FIR convention.
+	ConventionGeneralFIR Convention = "GeneralFIR"
+)
+
+// Dataset holds a SOFA file's directional data in memory, in a form that
+// can be converted to a daff.Writer (see ToWriter).
+type Dataset struct {
+	Convention   Convention
+	Samplerate   int
+	NumReceivers int
+	FilterLength int
+	// Directions holds one entry per measurement, in SOFA's SourcePosition
+	// order: azimuth and elevation in degrees.
+	Directions []Direction
+	// IR holds per-direction, per-receiver filter coefficients, indexed as
+	// IR[measurement][receiver].
+	IR [][][]float32
+}
+
+// Direction is a source position in spherical coordinates, degrees.
+type Direction struct {
+	Azimuth   float64
+	Elevation float64
+}
+
+// ReadFile opens a .sofa file and loads it into a Dataset. Only the
+// SimpleFreeFieldHRIR and GeneralFIR conventions are supported.
+func ReadFile(path string) (*Dataset, error) {
+	ds, err := netcdf.OpenFile(path, netcdf.NOWRITE)
+	if err != nil {
+		return nil, errors.New("failed to open SOFA file: " + path)
+	}
+	defer ds.Close()
+
+	convention, err := readGlobalStringAttr(ds, "SOFAConventions")
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Dataset{Convention: Convention(convention)}
+
+	irVar, err := ds.Var("Data.IR")
+	if err != nil {
+		return nil, errors.New("SOFA file has no Data.IR variable: " + path)
+	}
+
+	dims, err := varDims(irVar)
+	if err != nil {
+		return nil, err
+	}
+	if len(dims) != 3 {
+		return nil, errors.New("unsupported Data.IR rank in SOFA file: " + path)
+	}
+	numMeasurements, numReceivers, filterLength := dims[0], dims[1], dims[2]
+	out.NumReceivers = numReceivers
+	out.FilterLength = filterLength
+
+	samplingRateVar, err := ds.Var("Data.SamplingRate")
+	if err == nil {
+		rate := make([]float64, 1)
+		if err := samplingRateVar.ReadFloat64s(rate); err == nil {
+			out.Samplerate = int(rate[0])
+		}
+	}
+
+	sourcePosVar, err := ds.Var("SourcePosition")
+	if err != nil {
+		return nil, errors.New("SOFA file has no SourcePosition variable: " + path)
+	}
+	positions := make([]float64, numMeasurements*3)
+	if err := sourcePosVar.ReadFloat64s(positions); err != nil {
+		return nil, errors.New("failed to read SourcePosition: " + path)
+	}
+
+	irData := make([]float32, numMeasurements*numReceivers*filterLength)
+	if err := irVar.ReadFloat32s(irData); err != nil {
+		return nil, errors.New("failed to read Data.IR: " + path)
+	}
+
+	out.Directions = make([]Direction, numMeasurements)
+	out.IR = make([][][]float32, numMeasurements)
+	for m := 0; m < numMeasurements; m++ {
+		out.Directions[m] = Direction{
+			Azimuth:   positions[m*3],
+			Elevation: positions[m*3+1],
+		}
+		out.IR[m] = make([][]float32, numReceivers)
+		for r := 0; r < numReceivers; r++ {
+			start := (m*numReceivers + r) * filterLength
+			coeffs := make([]float32, filterLength)
+			copy(coeffs, irData[start:start+filterLength])
+			out.IR[m][r] = coeffs
+		}
+	}
+
+	return out, nil
+}
+
+// ToWriter converts the dataset into a daff.Writer populated with
+// ContentTypeIR records on a regular alpha/beta grid of the given
+// resolution. SOFA's irregular or scattered measurement grids are resampled
+// onto that grid by nearest-neighbour lookup.
+//
+// SOFA's azimuth/elevation (degrees, elevation measured from the horizontal
+// plane) is mapped onto DAFF's alpha/beta (alpha = azimuth, beta = 90 -
+// elevation, so beta=0 is the north pole).
+func (d *Dataset) ToWriter(alphaPoints, betaPoints int) (*daff.Writer, error) {
+	writer, err := daff.NewWriter(daff.ContentTypeIR, alphaPoints, betaPoints, d.NumReceivers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writer.SetSamplerate(d.Samplerate); err != nil {
+		writer.Close()
+		return nil, err
+	}
+
+	for a := 0; a < alphaPoints; a++ {
+		for b := 0; b < betaPoints; b++ {
+			alpha, beta := gridPoint(a, alphaPoints, b, betaPoints)
+			recordIndex := a*betaPoints + b
+			nearest := d.nearestMeasurement(alpha, beta)
+			for r := 0; r < d.NumReceivers; r++ {
+				if err := writer.SetFilterCoeffs(recordIndex, r, d.IR[nearest][r]); err != nil {
+					writer.Close()
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return writer, nil
+}
+
+// gridPoint returns the alpha/beta coordinates, in degrees, of grid point
+// (a, b) on a regular alphaPoints x betaPoints grid. A single beta ring
+// (betaPoints == 1, e.g. an equatorial-only grid) has no step between
+// points, so every point sits on the equator, beta=90.
+func gridPoint(a, alphaPoints, b, betaPoints int) (alpha, beta float64) {
+	alpha = float64(a) * 360.0 / float64(alphaPoints)
+	if betaPoints <= 1 {
+		return alpha, 90
+	}
+	return alpha, float64(b) * 180.0 / float64(betaPoints-1)
+}
+
+// nearestMeasurement finds the SOFA measurement whose direction is closest
+// to the given DAFF (alpha, beta) grid point, by great-circle distance.
+func (d *Dataset) nearestMeasurement(alpha, beta float64) int {
+	bestIdx := 0
+	bestDist := math.Inf(1)
+
+	targetAz := alpha * math.Pi / 180
+	targetEl := (90 - beta) * math.Pi / 180
+
+	for i, dir := range d.Directions {
+		az := dir.Azimuth * math.Pi / 180
+		el := dir.Elevation * math.Pi / 180
+		dist := greatCircleDistance(targetAz, targetEl, az, el)
+		if dist < bestDist {
+			bestDist = dist
+			bestIdx = i
+		}
+	}
+	return bestIdx
+}
+
+func greatCircleDistance(az1, el1, az2, el2 float64) float64 {
+	dot := math.Sin(el1)*math.Sin(el2) + math.Cos(el1)*math.Cos(el2)*math.Cos(az1-az2)
+	if dot > 1 {
+		dot = 1
+	} else if dot < -1 {
+		dot = -1
+	}
+	return math.Acos(dot)
+}
+
+func readGlobalStringAttr(ds netcdf.Dataset, name string) (string, error) {
+	attr := ds.Attr(name)
+	length, err := attr.Len()
+	if err != nil {
+		return "", errors.New("missing SOFA attribute: " + name)
+	}
+	buf := make([]byte, length)
+	if err := attr.ReadBytes(buf); err != nil {
+		return "", errors.New("failed to read SOFA attribute: " + name)
+	}
+	return string(buf), nil
+}
+
+// WriteFile exports an opened daff.Reader containing ContentTypeIR data to a
+// SOFA file using the SimpleFreeFieldHRIR convention.
+func WriteFile(reader *daff.Reader, path string) error {
+	if reader.GetContentType() != daff.ContentTypeIR {
+		return errors.New("SOFA export currently only supports impulse response content")
+	}
+	ir, err := reader.GetContentIR()
+	if err != nil {
+		return err
+	}
+
+	numMeasurements := reader.GetNumRecords()
+	numReceivers := reader.GetNumChannels()
+	filterLength := ir.GetFilterLength()
+
+	out, err := netcdf.CreateFile(path, netcdf.CLOBBER|netcdf.NETCDF4)
+	if err != nil {
+		return errors.New("failed to create SOFA file: " + path)
+	}
+	defer out.Close()
+
+	if err := out.Attr("SOFAConventions").WriteBytes([]byte(ConventionSimpleFreeFieldHRIR)); err != nil {
+		return err
+	}
+
+	measurementDim, err := out.AddDim("M", uint64(numMeasurements))
+	if err != nil {
+		return err
+	}
+	receiverDim, err := out.AddDim("R", uint64(numReceivers))
+	if err != nil {
+		return err
+	}
+	sampleDim, err := out.AddDim("N", uint64(filterLength))
+	if err != nil {
+		return err
+	}
+	coordDim, err := out.AddDim("C", 3)
+	if err != nil {
+		return err
+	}
+
+	irVar, err := out.AddVar("Data.IR", netcdf.FLOAT, []netcdf.Dim{measurementDim, receiverDim, sampleDim})
+	if err != nil {
+		return err
+	}
+	sourcePosVar, err := out.AddVar("SourcePosition", netcdf.DOUBLE, []netcdf.Dim{measurementDim, coordDim})
+	if err != nil {
+		return err
+	}
+	samplingRateVar, err := out.AddVar("Data.SamplingRate", netcdf.DOUBLE, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := samplingRateVar.WriteFloat64s([]float64{float64(ir.GetSamplerate())}); err != nil {
+		return err
+	}
+
+	irData := make([]float32, numMeasurements*numReceivers*filterLength)
+	positions := make([]float64, numMeasurements*3)
+	for m := 0; m < numMeasurements; m++ {
+		alpha, beta, err := ir.GetRecordCoords(m)
+		if err != nil {
+			return err
+		}
+		positions[m*3] = alpha
+		positions[m*3+1] = 90 - beta
+		positions[m*3+2] = 0
+
+		for r := 0; r < numReceivers; r++ {
+			coeffs, err := ir.GetFilterCoeffs(m, r)
+			if err != nil {
+				return err
+			}
+			copy(irData[(m*numReceivers+r)*filterLength:], coeffs)
+		}
+	}
+
+	if err := irVar.WriteFloat32s(irData); err != nil {
+		return err
+	}
+	if err := sourcePosVar.WriteFloat64s(positions); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func varDims(v netcdf.Var) ([]int, error) {
+	vdims, err := v.Dims()
+	if err != nil {
+		return nil, err
+	}
+	dims := make([]int, len(vdims))
+	for i, dim := range vdims {
+		length, err := dim.Len()
+		if err != nil {
+			return nil, err
+		}
+		dims[i] = int(length)
+	}
+	return dims, nil
+}
@@ -0,0 +1,47 @@
+package sofa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGreatCircleDistanceIdentical(t *testing.T) {
+	if d := greatCircleDistance(0, 0, 0, 0); d != 0 {
+		t.Errorf("expected 0 distance for identical directions, got %v", d)
+	}
+}
+
+func TestNearestMeasurement(t *testing.T) {
+	ds := &Dataset{
+		Directions: []Direction{
+			{Azimuth: 0, Elevation: 0},
+			{Azimuth: 90, Elevation: 0},
+			{Azimuth: 180, Elevation: 0},
+		},
+	}
+
+	if got := ds.nearestMeasurement(95, 90); got != 1 {
+		t.Errorf("expected nearest measurement 1, got %d", got)
+	}
+}
+
+func TestGridPointSingleBetaRing(t *testing.T) {
+	alpha, beta := gridPoint(2, 8, 0, 1)
+
+	if math.IsNaN(beta) || math.IsInf(beta, 0) {
+		t.Fatalf("expected a finite beta for a single-ring grid, got %v", beta)
+	}
+	if beta != 90 {
+		t.Errorf("beta = %v, want 90 (the equator) for a single-ring grid", beta)
+	}
+	if want := 2 * 360.0 / 8; alpha != want {
+		t.Errorf("alpha = %v, want %v", alpha, want)
+	}
+}
+
+func TestGridPointMultipleBetaRings(t *testing.T) {
+	_, beta := gridPoint(0, 8, 1, 3)
+	if want := 180.0 / 2; beta != want {
+		t.Errorf("beta = %v, want %v", beta, want)
+	}
+}
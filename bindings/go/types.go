@@ -0,0 +1,53 @@
+package daff
+
+// ContentType represents the type of data stored in a DAFF file
+type ContentType int
+
+const (
+	ContentTypeIR  ContentType = 1 // Impulse Response
+	ContentTypeMS  ContentType = 2 // Magnitude Spectrum
+	ContentTypePS  ContentType = 3 // Phase Spectrum
+	ContentTypeMPS ContentType = 4 // Magnitude-Phase Spectrum
+	ContentTypeDFT ContentType = 5 // DFT Coefficients
+)
+
+// String returns the string representation of the content type
+func (c ContentType) String() string {
+	switch c {
+	case ContentTypeIR:
+		return "ImpulseResponse"
+	case ContentTypeMS:
+		return "MagnitudeSpectrum"
+	case ContentTypePS:
+		return "PhaseSpectrum"
+	case ContentTypeMPS:
+		return "MagnitudePhaseSpectrum"
+	case ContentTypeDFT:
+		return "DFT"
+	default:
+		return "Unknown"
+	}
+}
+
+// Quantization represents the data quantization type
+type Quantization int
+
+const (
+	QuantizationInt16   Quantization = 1
+	QuantizationInt24   Quantization = 2
+	QuantizationFloat32 Quantization = 3
+)
+
+// String returns the string representation of the quantization type
+func (q Quantization) String() string {
+	switch q {
+	case QuantizationInt16:
+		return "Int16"
+	case QuantizationInt24:
+		return "Int24"
+	case QuantizationFloat32:
+		return "Float32"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,203 @@
+//go:build !nocgo
+
+// Package daff (writer.go) adds authoring support on top of the read-only
+// Reader defined in daff.go: building new DAFF files from measurements,
+// simulations, or data converted from other formats.
+package daff
+
+/*
+#cgo CXXFLAGS: -I../../include -std=c++11
+#cgo LDFLAGS: -L../../build -lDAFF -lstdc++
+#include "daff_go_wrapper.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"unsafe"
+)
+
+// Writer builds a new DAFF file in memory and serializes it to disk.
+//
+// A Writer is created for a single content type via NewWriter and must be
+// closed with Close once writing is finished. Per-record data is supplied
+// with the Set* methods before calling WriteFile or Write.
+type Writer struct {
+	handle      C.GoDAFFWriterHandle
+	contentType ContentType
+}
+
+// NewWriter creates a new DAFF writer for the given content type.
+//
+// alphaPoints and betaPoints specify the angular grid resolution; channels
+// is the number of audio channels (e.g. 2 for a binaural HRTF set).
+func NewWriter(contentType ContentType, alphaPoints, betaPoints, channels int) (*Writer, error) {
+	handle := C.GoDAFF_Writer_Create(C.int(contentType), C.int(alphaPoints), C.int(betaPoints), C.int(channels))
+	if handle == nil {
+		return nil, errors.New("failed to create DAFF writer")
+	}
+
+	writer := &Writer{handle: handle, contentType: contentType}
+	runtime.SetFinalizer(writer, (*Writer).Close)
+	return writer, nil
+}
+
+// Close releases resources associated with the writer.
+func (w *Writer) Close() error {
+	if w.handle != nil {
+		C.GoDAFF_Writer_Destroy(w.handle)
+		w.handle = nil
+	}
+	return nil
+}
+
+// SetQuantization sets the sample quantization used when writing the file.
+func (w *Writer) SetQuantization(q Quantization) error {
+	if !C.GoDAFF_Writer_SetQuantization(w.handle, C.int(q)) {
+		return errors.New("failed to set quantization")
+	}
+	return nil
+}
+
+// SetOrientation sets the orientation as yaw, pitch, roll angles in degrees.
+func (w *Writer) SetOrientation(yaw, pitch, roll float32) error {
+	if !C.GoDAFF_Writer_SetOrientationYPR(w.handle, C.float(yaw), C.float(pitch), C.float(roll)) {
+		return errors.New("failed to set orientation")
+	}
+	return nil
+}
+
+// SetSamplerate sets the sample rate in Hz. Only meaningful for IR content.
+func (w *Writer) SetSamplerate(samplerate int) error {
+	if !C.GoDAFF_Writer_SetSamplerate(w.handle, C.int(samplerate)) {
+		return errors.New("failed to set samplerate")
+	}
+	return nil
+}
+
+// SetMetadataString sets a string metadata value, mirroring Reader.GetMetadataString.
+func (w *Writer) SetMetadataString(key, value string) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+
+	if !C.GoDAFF_Writer_SetMetadataString(w.handle, cKey, cValue) {
+		return errors.New("failed to set metadata: " + key)
+	}
+	return nil
+}
+
+// SetMetadataFloat sets a float metadata value.
+func (w *Writer) SetMetadataFloat(key string, value float32) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	if !C.GoDAFF_Writer_SetMetadataFloat(w.handle, cKey, C.float(value)) {
+		return errors.New("failed to set metadata: " + key)
+	}
+	return nil
+}
+
+// SetMetadataBool sets a boolean metadata value.
+func (w *Writer) SetMetadataBool(key string, value bool) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	if !C.GoDAFF_Writer_SetMetadataBool(w.handle, cKey, C.bool(value)) {
+		return errors.New("failed to set metadata: " + key)
+	}
+	return nil
+}
+
+// SetFilterCoeffs sets the IR filter coefficients for a record and channel.
+// Only valid when the writer was created with ContentTypeIR.
+func (w *Writer) SetFilterCoeffs(recordIndex, channel int, coeffs []float32) error {
+	if len(coeffs) == 0 {
+		return errors.New("coeffs must not be empty")
+	}
+	if !C.GoDAFF_Writer_SetFilterCoeffs(w.handle, C.int(recordIndex), C.int(channel),
+		(*C.float)(unsafe.Pointer(&coeffs[0])), C.int(len(coeffs))) {
+		return errors.New("failed to set filter coefficients")
+	}
+	return nil
+}
+
+// SetMagnitudes sets the magnitude spectrum for a record and channel.
+// Only valid when the writer was created with ContentTypeMS or ContentTypeMPS.
+func (w *Writer) SetMagnitudes(recordIndex, channel int, magnitudes []float32) error {
+	if len(magnitudes) == 0 {
+		return errors.New("magnitudes must not be empty")
+	}
+	if !C.GoDAFF_Writer_SetMagnitudes(w.handle, C.int(recordIndex), C.int(channel),
+		(*C.float)(unsafe.Pointer(&magnitudes[0])), C.int(len(magnitudes))) {
+		return errors.New("failed to set magnitudes")
+	}
+	return nil
+}
+
+// SetPhases sets the phase spectrum for a record and channel.
+// Only valid when the writer was created with ContentTypePS or ContentTypeMPS.
+func (w *Writer) SetPhases(recordIndex, channel int, phases []float32) error {
+	if len(phases) == 0 {
+		return errors.New("phases must not be empty")
+	}
+	if !C.GoDAFF_Writer_SetPhases(w.handle, C.int(recordIndex), C.int(channel),
+		(*C.float)(unsafe.Pointer(&phases[0])), C.int(len(phases))) {
+		return errors.New("failed to set phases")
+	}
+	return nil
+}
+
+// SetDFTCoeffs sets the DFT coefficients (interleaved real/imaginary) for a
+// record and channel. Only valid when the writer was created with ContentTypeDFT.
+func (w *Writer) SetDFTCoeffs(recordIndex, channel int, coeffs []float32) error {
+	if len(coeffs) == 0 || len(coeffs)%2 != 0 {
+		return errors.New("coeffs must be a non-empty interleaved real/imaginary sequence")
+	}
+	if !C.GoDAFF_Writer_SetDFTCoeffs(w.handle, C.int(recordIndex), C.int(channel),
+		(*C.float)(unsafe.Pointer(&coeffs[0])), C.int(len(coeffs))) {
+		return errors.New("failed to set DFT coefficients")
+	}
+	return nil
+}
+
+// WriteFile serializes the writer's content to a DAFF file at path.
+func (w *Writer) WriteFile(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	if !C.GoDAFF_Writer_WriteFile(w.handle, cPath) {
+		return errors.New("failed to write file: " + path)
+	}
+	return nil
+}
+
+// Write serializes the writer's content to an arbitrary io.Writer. Since the
+// underlying C++ library only writes to paths on disk, the content is
+// staged through a temporary file and copied out.
+func (w *Writer) Write(dst io.Writer) error {
+	tmp, err := os.CreateTemp("", "daff-writer-*.daff")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	if err := w.WriteFile(tmpPath); err != nil {
+		return err
+	}
+
+	f, err := os.Open(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(dst, f)
+	return err
+}